@@ -11,12 +11,12 @@ import (
 func ExampleMemFS() {
 	rootFS := memfs.New()
 
-	err := rootFS.MkdirAll("dir1/dir2")
+	err := rootFS.MkdirAll("dir1/dir2", 0755)
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	err = rootFS.WriteFile("dir1/dir2/f1.txt", []byte("incinerating-unsubstantial"))
+	err = rootFS.WriteFile("dir1/dir2/f1.txt", []byte("incinerating-unsubstantial"), 0644)
 	if err != nil {
 		log.Fatal(err)
 	}
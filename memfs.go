@@ -1,12 +1,18 @@
 package memfs
 
 import (
-	"bytes"
+	"archive/tar"
+	"archive/zip"
 	"errors"
 	"fmt"
+	"io"
 	"io/fs"
+	"os"
+	"path"
 	"path/filepath"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -14,6 +20,8 @@ import (
 type dir struct {
 	*dirEntry
 
+	fsys *FS
+
 	entries map[string]fs.DirEntry
 
 	names []string
@@ -21,6 +29,17 @@ type dir struct {
 
 var _ fs.ReadDirFile = (*dir)(nil)
 
+// open returns a handle onto d sharing its entries but starting with a
+// fresh ReadDir cursor, so each Open of the same directory paginates
+// independently instead of racing over d.names.
+func (d *dir) open() *dir {
+	return &dir{
+		dirEntry: d.dirEntry,
+		fsys:     d.fsys,
+		entries:  d.entries,
+	}
+}
+
 func (d *dir) Read(p []byte) (int, error) {
 	return 0, &fs.PathError{
 		Op:   "read",
@@ -38,11 +57,17 @@ func (d *dir) Close() error {
 }
 
 func (d *dir) ReadDir(n int) ([]fs.DirEntry, error) {
+	// ReadDir advances d.names, so even concurrent readers of the same
+	// handle need exclusive access.
+	d.fsys.mu.Lock()
+	defer d.fsys.mu.Unlock()
+
 	if d.names == nil {
 		d.names = make([]string, 0, len(d.entries))
 		for name := range d.entries {
 			d.names = append(d.names, name)
 		}
+		sort.Strings(d.names)
 	}
 
 	if n <= 0 {
@@ -70,6 +95,9 @@ type fileInfo struct {
 	size    int64
 	modTime time.Time
 	mode    fs.FileMode
+
+	atime    time.Time
+	uid, gid int
 }
 
 var _ fs.FileInfo = (*fileInfo)(nil)
@@ -94,8 +122,21 @@ func (fi *fileInfo) IsDir() bool {
 	return fi.Mode().IsDir()
 }
 
+// Sys returns a *Sys value exposing the metadata that fs.FileInfo has
+// no field for: access time, uid, and gid.
 func (fi *fileInfo) Sys() interface{} {
-	return nil
+	return &Sys{
+		Atime: fi.atime,
+		Uid:   fi.uid,
+		Gid:   fi.gid,
+	}
+}
+
+// Sys is the value returned by a memfs fs.FileInfo's Sys method.
+type Sys struct {
+	Atime time.Time
+	Uid   int
+	Gid   int
 }
 
 // Implements fs.DirEntry
@@ -110,25 +151,188 @@ func (de *dirEntry) Type() fs.FileMode {
 }
 
 func (de *dirEntry) Info() (fs.FileInfo, error) {
-	return de.fileInfo, nil
+	// Return a copy so callers never read or write the tree's live
+	// fileInfo outside the FS lock; Chmod/Chtimes/Chown mutate the
+	// original in place.
+	fi := *de.fileInfo
+	return &fi, nil
+}
+
+// File is a handle to a file opened with (*FS).OpenFile. In addition to
+// fs.File, it supports the writing and seeking operations a caller needs
+// to incrementally build up a file's content, mirroring what
+// golang.org/x/net/webdav.FileSystem and *os.File expose.
+type File interface {
+	fs.File
+	io.Writer
+	io.Seeker
+
+	// Truncate changes the size of the file.
+	Truncate(size int64) error
+
+	// Sync flushes any content buffered in the handle back to the FS.
+	// Close does this automatically.
+	Sync() error
 }
 
-// implement fs.File
+// implement fs.File and File
 type file struct {
 	*dirEntry
 
-	content *bytes.Buffer
-	closed  bool
+	data   []byte
+	pos    int64
+	flag   int
+	closed bool
+
+	// parent is the directory that Sync and Close flush data back to,
+	// under the handle's name. It is nil for read-only handles returned
+	// by Open, which never write back.
+	parent *dir
 }
 
-var _ fs.File = (*file)(nil)
+var _ File = (*file)(nil)
 
 func (f *file) Read(p []byte) (int, error) {
 	if f.closed {
 		return 0, fs.ErrClosed
 	}
 
-	return f.content.Read(p)
+	if f.flag&(os.O_WRONLY) != 0 {
+		return 0, &fs.PathError{Op: "read", Path: f.name, Err: fs.ErrPermission}
+	}
+
+	if f.pos >= int64(len(f.data)) {
+		return 0, io.EOF
+	}
+
+	n := copy(p, f.data[f.pos:])
+	f.pos += int64(n)
+
+	return n, nil
+}
+
+func (f *file) Write(p []byte) (int, error) {
+	if f.closed {
+		return 0, fs.ErrClosed
+	}
+
+	if f.parent == nil || f.flag&(os.O_WRONLY|os.O_RDWR) == 0 {
+		return 0, &fs.PathError{Op: "write", Path: f.name, Err: fs.ErrPermission}
+	}
+
+	if f.flag&os.O_APPEND != 0 {
+		f.pos = int64(len(f.data))
+	}
+
+	end := f.pos + int64(len(p))
+	if end > int64(len(f.data)) {
+		grown := make([]byte, end)
+		copy(grown, f.data)
+		f.data = grown
+	}
+
+	n := copy(f.data[f.pos:end], p)
+	f.pos += int64(n)
+	f.modTime = time.Now()
+
+	return n, nil
+}
+
+func (f *file) Seek(offset int64, whence int) (int64, error) {
+	if f.closed {
+		return 0, fs.ErrClosed
+	}
+
+	var abs int64
+	switch whence {
+	case io.SeekStart:
+		abs = offset
+	case io.SeekCurrent:
+		abs = f.pos + offset
+	case io.SeekEnd:
+		abs = int64(len(f.data)) + offset
+	default:
+		return 0, &fs.PathError{Op: "seek", Path: f.name, Err: fs.ErrInvalid}
+	}
+
+	if abs < 0 {
+		return 0, &fs.PathError{Op: "seek", Path: f.name, Err: fs.ErrInvalid}
+	}
+
+	f.pos = abs
+	return abs, nil
+}
+
+func (f *file) Truncate(size int64) error {
+	if f.closed {
+		return fs.ErrClosed
+	}
+
+	if f.parent == nil || f.flag&(os.O_WRONLY|os.O_RDWR) == 0 {
+		return &fs.PathError{Op: "truncate", Path: f.name, Err: fs.ErrPermission}
+	}
+
+	if size < 0 {
+		return &fs.PathError{Op: "truncate", Path: f.name, Err: fs.ErrInvalid}
+	}
+
+	if size <= int64(len(f.data)) {
+		f.data = f.data[:size]
+	} else {
+		grown := make([]byte, size)
+		copy(grown, f.data)
+		f.data = grown
+	}
+
+	f.modTime = time.Now()
+	return nil
+}
+
+func (f *file) Sync() error {
+	if f.closed {
+		return fs.ErrClosed
+	}
+
+	f.flush()
+	return nil
+}
+
+// flush commits the handle's buffered data back into the parent
+// directory's entries, as a fresh, immutable snapshot. Handles opened
+// via Open have no parent and never flush.
+func (f *file) flush() {
+	if f.parent == nil {
+		return
+	}
+
+	f.parent.fsys.mu.Lock()
+	defer f.parent.fsys.mu.Unlock()
+
+	f.flushLocked()
+}
+
+// flushLocked is flush without acquiring fsys.mu, for callers that
+// already hold the write lock.
+func (f *file) flushLocked() {
+	data := make([]byte, len(f.data))
+	copy(data, f.data)
+
+	f.size = int64(len(data))
+	f.parent.entries[f.name] = &file{
+		dirEntry: &dirEntry{
+			fileInfo: &fileInfo{
+				name:    f.name,
+				size:    f.size,
+				modTime: f.modTime,
+				mode:    f.mode,
+				atime:   f.atime,
+				uid:     f.uid,
+				gid:     f.gid,
+			},
+		},
+
+		data: data,
+	}
 }
 
 func (f *file) Stat() (fs.FileInfo, error) {
@@ -137,10 +341,10 @@ func (f *file) Stat() (fs.FileInfo, error) {
 	}
 
 	info, _ := f.dirEntry.Info()
-	fi := info.(*fileInfo)
-	fi.size = int64(f.content.Len())
+	fi := *info.(*fileInfo)
+	fi.size = int64(len(f.data))
 
-	return fi, nil
+	return &fi, nil
 }
 
 func (f *file) Close() error {
@@ -148,34 +352,46 @@ func (f *file) Close() error {
 		return fs.ErrClosed
 	}
 
+	f.flush()
 	f.closed = true
 	return nil
 }
 
 // FS is an in-memory filesystem that implements
-// io/fs.FS
+// io/fs.FS. It is safe for concurrent use by multiple goroutines.
 type FS struct {
+	mu *sync.RWMutex
+
 	root *dir
 }
 
 var _ fs.FS = (*FS)(nil)
+var _ fs.SubFS = (*FS)(nil)
+var _ fs.GlobFS = (*FS)(nil)
+var _ fs.ReadFileFS = (*FS)(nil)
+var _ fs.ReadDirFS = (*FS)(nil)
+var _ fs.StatFS = (*FS)(nil)
 
 // New creates a new in-memory FileSystem.
 func New() *FS {
-	return &FS{
-		root: &dir{
-			dirEntry: &dirEntry{
-				fileInfo: &fileInfo{
-					name:    ".",
-					size:    0,
-					modTime: time.Now(),
-					mode:    fs.ModeDir | 0644,
-				},
+	fsys := &FS{mu: new(sync.RWMutex)}
+	now := time.Now()
+	fsys.root = &dir{
+		dirEntry: &dirEntry{
+			fileInfo: &fileInfo{
+				name:    ".",
+				size:    0,
+				modTime: now,
+				mode:    fs.ModeDir | 0644,
+				atime:   now,
 			},
-
-			entries: make(map[string]fs.DirEntry),
 		},
+
+		fsys:    fsys,
+		entries: make(map[string]fs.DirEntry),
 	}
+
+	return fsys
 }
 
 // Open opens the named file.
@@ -188,8 +404,11 @@ func (fsys *FS) Open(name string) (fs.File, error) {
 		}
 	}
 
+	fsys.mu.RLock()
+	defer fsys.mu.RUnlock()
+
 	if name == "." || name == "" {
-		return fsys.root, nil
+		return fsys.root.open(), nil
 	}
 
 	var cur *dir = fsys.root
@@ -204,9 +423,12 @@ func (fsys *FS) Open(name string) (fs.File, error) {
 		f, ok := entry.(*file)
 		if ok {
 			if i == len(parts)-1 {
+				data := make([]byte, len(f.data))
+				copy(data, f.data)
+
 				return &file{
 					dirEntry: f.dirEntry,
-					content:  bytes.NewBuffer(f.content.Bytes()),
+					data:     data,
 				}, nil
 			}
 
@@ -221,7 +443,7 @@ func (fsys *FS) Open(name string) (fs.File, error) {
 		cur = d
 	}
 
-	return cur, nil
+	return cur.open(), nil
 
 errNotExist:
 	return nil, &fs.PathError{
@@ -231,12 +453,12 @@ errNotExist:
 	}
 }
 
-// MkdirAll creates a directory named path,
-// along with any necessary parents, and returns nil,
-// or else returns an error.
-// If path is already a directory, MkdirAll does nothing
-// and returns nil.
-func (fsys *FS) MkdirAll(path string) error {
+// MkdirAll creates a directory named path, along with any necessary
+// parents, with permission bits perm (any new directory's fs.ModeDir
+// bit is set automatically), and returns nil, or else returns an
+// error. If path is already a directory, MkdirAll does nothing and
+// returns nil.
+func (fsys *FS) MkdirAll(path string, perm fs.FileMode) error {
 	if !fs.ValidPath(path) {
 		return fs.ErrInvalid
 	}
@@ -245,21 +467,27 @@ func (fsys *FS) MkdirAll(path string) error {
 		return nil
 	}
 
+	fsys.mu.Lock()
+	defer fsys.mu.Unlock()
+
 	var cur *dir = fsys.root
 	parts := strings.Split(path, "/")
 	for _, part := range parts {
 		entry := cur.entries[part]
 		if entry == nil {
+			now := time.Now()
 			entry = &dir{
 				dirEntry: &dirEntry{
 					fileInfo: &fileInfo{
 						name:    part,
 						size:    0,
-						modTime: time.Now(),
-						mode:    fs.ModeDir | 0644,
+						modTime: now,
+						mode:    fs.ModeDir | perm.Perm(),
+						atime:   now,
 					},
 				},
 
+				fsys:    fsys,
 				entries: make(map[string]fs.DirEntry),
 			}
 
@@ -276,10 +504,10 @@ func (fsys *FS) MkdirAll(path string) error {
 	return nil
 }
 
-// WriteFile writes data to a file named by filename.
-// If the file does not exist, WriteFile creates it;
+// WriteFile writes data to a file named by filename with permission
+// bits perm. If the file does not exist, WriteFile creates it;
 // otherwise WriteFile truncates it before writing.
-func (fsys *FS) WriteFile(name string, data []byte) error {
+func (fsys *FS) WriteFile(name string, data []byte, perm fs.FileMode) error {
 	if !fs.ValidPath(name) {
 		return &fs.PathError{
 			Op:   "write",
@@ -288,6 +516,9 @@ func (fsys *FS) WriteFile(name string, data []byte) error {
 		}
 	}
 
+	fsys.mu.Lock()
+	defer fsys.mu.Unlock()
+
 	var err error
 	var dir *dir = fsys.root
 	path := filepath.Dir(name)
@@ -301,23 +532,569 @@ func (fsys *FS) WriteFile(name string, data []byte) error {
 	buf := make([]byte, len(data))
 	copy(buf, data)
 
+	now := time.Now()
 	filename := filepath.Base(name)
 	dir.entries[filename] = &file{
 		dirEntry: &dirEntry{
 			fileInfo: &fileInfo{
 				name:    filename,
 				size:    int64(len(data)),
-				modTime: time.Now(),
-				mode:    0644,
+				modTime: now,
+				mode:    perm.Perm(),
+				atime:   now,
+			},
+		},
+
+		data: buf,
+	}
+
+	return nil
+}
+
+// OpenFile opens the named file with the given flag (os.O_RDONLY,
+// os.O_WRONLY, os.O_RDWR, os.O_APPEND, os.O_CREATE, os.O_TRUNC,
+// os.O_EXCL, or a combination thereof) and perm, returning a writable,
+// seekable File. This mirrors os.OpenFile and is how callers make
+// incremental writes instead of replacing a file's whole content with
+// WriteFile.
+func (fsys *FS) OpenFile(name string, flag int, perm fs.FileMode) (File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{
+			Op:   "open",
+			Path: name,
+			Err:  fs.ErrInvalid,
+		}
+	}
+
+	fsys.mu.Lock()
+	defer fsys.mu.Unlock()
+
+	var parent *dir = fsys.root
+	path := filepath.Dir(name)
+	if path != "." {
+		var err error
+		parent, err = fsys.getDir(path)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	filename := filepath.Base(name)
+	entry := parent.entries[filename]
+
+	if entry == nil {
+		if flag&os.O_CREATE == 0 {
+			return nil, &fs.PathError{
+				Op:   "open",
+				Path: name,
+				Err:  fs.ErrNotExist,
+			}
+		}
+
+		now := time.Now()
+		f := &file{
+			dirEntry: &dirEntry{
+				fileInfo: &fileInfo{
+					name:    filename,
+					modTime: now,
+					mode:    perm.Perm(),
+					atime:   now,
+				},
+			},
+
+			parent: parent,
+			flag:   flag,
+		}
+		f.flushLocked()
+
+		return f, nil
+	}
+
+	if flag&os.O_EXCL != 0 {
+		return nil, &fs.PathError{
+			Op:   "open",
+			Path: name,
+			Err:  fs.ErrExist,
+		}
+	}
+
+	existing, ok := entry.(*file)
+	if !ok {
+		return nil, &fs.PathError{
+			Op:   "open",
+			Path: name,
+			Err:  errors.New("is a directory"),
+		}
+	}
+
+	data := existing.data
+	if flag&os.O_TRUNC != 0 {
+		data = nil
+	}
+
+	buf := make([]byte, len(data))
+	copy(buf, data)
+
+	f := &file{
+		dirEntry: &dirEntry{
+			fileInfo: &fileInfo{
+				name:    filename,
+				size:    int64(len(buf)),
+				modTime: existing.modTime,
+				mode:    existing.mode,
+				atime:   existing.atime,
+				uid:     existing.uid,
+				gid:     existing.gid,
 			},
 		},
 
-		content: bytes.NewBuffer(buf),
+		data:   buf,
+		parent: parent,
+		flag:   flag,
+	}
+
+	if flag&os.O_APPEND != 0 {
+		f.pos = int64(len(f.data))
 	}
 
+	return f, nil
+}
+
+// Rename renames (moves) oldpath to newpath. If newpath already exists
+// and is not a directory, Rename replaces it. It is an error if newpath
+// is an existing, non-empty directory. The parent directory of newpath
+// must already exist; Rename does not create intermediate directories.
+func (fsys *FS) Rename(oldpath, newpath string) error {
+	if !fs.ValidPath(oldpath) {
+		return &fs.PathError{Op: "rename", Path: oldpath, Err: fs.ErrInvalid}
+	}
+	if !fs.ValidPath(newpath) {
+		return &fs.PathError{Op: "rename", Path: newpath, Err: fs.ErrInvalid}
+	}
+
+	fsys.mu.Lock()
+	defer fsys.mu.Unlock()
+
+	oldParent := fsys.root
+	if path := filepath.Dir(oldpath); path != "." {
+		var err error
+		oldParent, err = fsys.getDir(path)
+		if err != nil {
+			return &fs.PathError{Op: "rename", Path: oldpath, Err: fs.ErrNotExist}
+		}
+	}
+
+	oldName := filepath.Base(oldpath)
+	entry := oldParent.entries[oldName]
+	if entry == nil {
+		return &fs.PathError{Op: "rename", Path: oldpath, Err: fs.ErrNotExist}
+	}
+
+	newParent := fsys.root
+	if path := filepath.Dir(newpath); path != "." {
+		var err error
+		newParent, err = fsys.getDir(path)
+		if err != nil {
+			return &fs.PathError{Op: "rename", Path: newpath, Err: fs.ErrNotExist}
+		}
+	}
+
+	newName := filepath.Base(newpath)
+	if newParent == oldParent && newName == oldName {
+		return nil
+	}
+
+	if dest, ok := newParent.entries[newName]; ok {
+		if destDir, ok := dest.(*dir); ok && len(destDir.entries) > 0 {
+			return &fs.PathError{Op: "rename", Path: newpath, Err: errors.New("directory not empty")}
+		}
+	}
+
+	switch e := entry.(type) {
+	case *file:
+		newParent.entries[newName] = &file{
+			dirEntry: &dirEntry{
+				fileInfo: &fileInfo{
+					name:    newName,
+					size:    e.size,
+					modTime: e.modTime,
+					mode:    e.mode,
+					atime:   e.atime,
+					uid:     e.uid,
+					gid:     e.gid,
+				},
+			},
+
+			data: e.data,
+		}
+	case *dir:
+		newParent.entries[newName] = &dir{
+			dirEntry: &dirEntry{
+				fileInfo: &fileInfo{
+					name:    newName,
+					size:    e.size,
+					modTime: e.modTime,
+					mode:    e.mode,
+					atime:   e.atime,
+					uid:     e.uid,
+					gid:     e.gid,
+				},
+			},
+
+			fsys:    fsys,
+			entries: e.entries,
+		}
+	}
+
+	delete(oldParent.entries, oldName)
+
 	return nil
 }
 
+// Remove removes the named file or empty directory.
+func (fsys *FS) Remove(name string) error {
+	if !fs.ValidPath(name) {
+		return &fs.PathError{Op: "remove", Path: name, Err: fs.ErrInvalid}
+	}
+
+	fsys.mu.Lock()
+	defer fsys.mu.Unlock()
+
+	parent := fsys.root
+	if path := filepath.Dir(name); path != "." {
+		var err error
+		parent, err = fsys.getDir(path)
+		if err != nil {
+			return &fs.PathError{Op: "remove", Path: name, Err: fs.ErrNotExist}
+		}
+	}
+
+	base := filepath.Base(name)
+	entry := parent.entries[base]
+	if entry == nil {
+		return &fs.PathError{Op: "remove", Path: name, Err: fs.ErrNotExist}
+	}
+
+	if d, ok := entry.(*dir); ok && len(d.entries) > 0 {
+		return &fs.PathError{Op: "remove", Path: name, Err: errors.New("directory not empty")}
+	}
+
+	delete(parent.entries, base)
+
+	return nil
+}
+
+// RemoveAll removes path and any children it contains. It removes
+// everything it can but returns the first error it encounters. If path
+// does not exist, RemoveAll returns nil.
+func (fsys *FS) RemoveAll(path string) error {
+	if !fs.ValidPath(path) {
+		return &fs.PathError{Op: "removeall", Path: path, Err: fs.ErrInvalid}
+	}
+
+	fsys.mu.Lock()
+	defer fsys.mu.Unlock()
+
+	if path == "." {
+		fsys.root.entries = make(map[string]fs.DirEntry)
+		return nil
+	}
+
+	parent := fsys.root
+	if dirPath := filepath.Dir(path); dirPath != "." {
+		var err error
+		parent, err = fsys.getDir(dirPath)
+		if err != nil {
+			return nil
+		}
+	}
+
+	delete(parent.entries, filepath.Base(path))
+
+	return nil
+}
+
+// Chmod changes the permission bits of the named file or directory,
+// leaving its type bits (e.g. fs.ModeDir) untouched.
+func (fsys *FS) Chmod(name string, mode fs.FileMode) error {
+	if !fs.ValidPath(name) {
+		return &fs.PathError{Op: "chmod", Path: name, Err: fs.ErrInvalid}
+	}
+
+	fsys.mu.Lock()
+	defer fsys.mu.Unlock()
+
+	fi, err := fsys.lookup(name)
+	if err != nil {
+		return &fs.PathError{Op: "chmod", Path: name, Err: err}
+	}
+
+	fi.mode = fi.mode.Type() | mode.Perm()
+
+	return nil
+}
+
+// Chtimes changes the access and modification times of the named file
+// or directory.
+func (fsys *FS) Chtimes(name string, atime, mtime time.Time) error {
+	if !fs.ValidPath(name) {
+		return &fs.PathError{Op: "chtimes", Path: name, Err: fs.ErrInvalid}
+	}
+
+	fsys.mu.Lock()
+	defer fsys.mu.Unlock()
+
+	fi, err := fsys.lookup(name)
+	if err != nil {
+		return &fs.PathError{Op: "chtimes", Path: name, Err: err}
+	}
+
+	fi.atime = atime
+	fi.modTime = mtime
+
+	return nil
+}
+
+// Chown changes the uid and gid of the named file or directory. The
+// values are only ever set and returned through fileInfo.Sys; memfs
+// does not otherwise interpret or enforce them.
+func (fsys *FS) Chown(name string, uid, gid int) error {
+	if !fs.ValidPath(name) {
+		return &fs.PathError{Op: "chown", Path: name, Err: fs.ErrInvalid}
+	}
+
+	fsys.mu.Lock()
+	defer fsys.mu.Unlock()
+
+	fi, err := fsys.lookup(name)
+	if err != nil {
+		return &fs.PathError{Op: "chown", Path: name, Err: err}
+	}
+
+	fi.uid = uid
+	fi.gid = gid
+
+	return nil
+}
+
+// lookup returns the live *fileInfo backing name, whether it is a file
+// or a directory, so Chmod/Chtimes/Chown can mutate metadata in place.
+func (fsys *FS) lookup(name string) (*fileInfo, error) {
+	if name == "." {
+		return fsys.root.fileInfo, nil
+	}
+
+	parent := fsys.root
+	if path := filepath.Dir(name); path != "." {
+		var err error
+		parent, err = fsys.getDir(path)
+		if err != nil {
+			return nil, fs.ErrNotExist
+		}
+	}
+
+	switch e := parent.entries[filepath.Base(name)].(type) {
+	case *file:
+		return e.fileInfo, nil
+	case *dir:
+		return e.fileInfo, nil
+	default:
+		return nil, fs.ErrNotExist
+	}
+}
+
+// Sub returns an FS corresponding to the subtree rooted at dir. It
+// shares the same underlying directory entries as fsys, so writes made
+// through either FS are visible through both.
+func (fsys *FS) Sub(dirPath string) (fs.FS, error) {
+	if dirPath == "." {
+		return fsys, nil
+	}
+
+	if !fs.ValidPath(dirPath) {
+		return nil, &fs.PathError{Op: "sub", Path: dirPath, Err: fs.ErrInvalid}
+	}
+
+	fsys.mu.RLock()
+	d, err := fsys.getDir(dirPath)
+	fsys.mu.RUnlock()
+	if err != nil {
+		return nil, &fs.PathError{Op: "sub", Path: dirPath, Err: fs.ErrNotExist}
+	}
+
+	return &FS{mu: fsys.mu, root: d}, nil
+}
+
+// Stat returns a FileInfo describing the named file.
+func (fsys *FS) Stat(name string) (fs.FileInfo, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrInvalid}
+	}
+
+	fsys.mu.RLock()
+	defer fsys.mu.RUnlock()
+
+	if name == "." || name == "" {
+		return fsys.root.dirEntry.Info()
+	}
+
+	parent := fsys.root
+	if dirPath := filepath.Dir(name); dirPath != "." {
+		var err error
+		parent, err = fsys.getDir(dirPath)
+		if err != nil {
+			return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrNotExist}
+		}
+	}
+
+	entry, ok := parent.entries[filepath.Base(name)]
+	if !ok {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrNotExist}
+	}
+
+	return entry.Info()
+}
+
+// ReadDir reads the named directory and returns a list of directory
+// entries sorted by filename.
+func (fsys *FS) ReadDir(name string) ([]fs.DirEntry, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	d, ok := f.(fs.ReadDirFile)
+	if !ok {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: errors.New("not a directory")}
+	}
+
+	return d.ReadDir(-1)
+}
+
+// ReadFile reads the named file and returns its contents.
+func (fsys *FS) ReadFile(name string) ([]byte, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "readfile", Path: name, Err: fs.ErrInvalid}
+	}
+
+	fsys.mu.RLock()
+	defer fsys.mu.RUnlock()
+
+	parent := fsys.root
+	if dirPath := filepath.Dir(name); dirPath != "." {
+		var err error
+		parent, err = fsys.getDir(dirPath)
+		if err != nil {
+			return nil, &fs.PathError{Op: "readfile", Path: name, Err: fs.ErrNotExist}
+		}
+	}
+
+	f, ok := parent.entries[filepath.Base(name)].(*file)
+	if !ok {
+		return nil, &fs.PathError{Op: "readfile", Path: name, Err: fs.ErrNotExist}
+	}
+
+	data := make([]byte, len(f.data))
+	copy(data, f.data)
+
+	return data, nil
+}
+
+// Glob returns the names of all files matching pattern, or nil if
+// there is no matching file. The syntax of patterns is the same as in
+// path.Match.
+func (fsys *FS) Glob(pattern string) ([]string, error) {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, err
+	}
+
+	if !hasMeta(pattern) {
+		if _, err := fsys.Stat(pattern); err != nil {
+			return nil, nil
+		}
+
+		return []string{pattern}, nil
+	}
+
+	dir, file := path.Split(pattern)
+	dir = cleanGlobPath(dir)
+
+	if !hasMeta(dir) {
+		return globDir(fsys, dir, file, nil)
+	}
+
+	dirs, err := fsys.Glob(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []string
+	for _, d := range dirs {
+		matches, err = globDir(fsys, d, file, matches)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return matches, nil
+}
+
+// cleanGlobPath prepares path for glob matching.
+func cleanGlobPath(path string) string {
+	if path == "" {
+		return "."
+	}
+
+	return path[:len(path)-1]
+}
+
+// globDir searches dir for entries matching pattern and appends them
+// to matches, mirroring the unexported algorithm io/fs.Glob falls back
+// to for an FS that doesn't implement GlobFS.
+func globDir(fsys fs.FS, dir, pattern string, matches []string) ([]string, error) {
+	if pattern == "" {
+		return matches, nil
+	}
+
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, nil
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+
+		ok, err := path.Match(pattern, name)
+		if err != nil {
+			return nil, err
+		}
+
+		if ok {
+			if dir == "." {
+				matches = append(matches, name)
+			} else {
+				matches = append(matches, dir+"/"+name)
+			}
+		}
+	}
+
+	return matches, nil
+}
+
+// hasMeta reports whether path contains any of the magic characters
+// recognized by path.Match.
+func hasMeta(path string) bool {
+	for i := 0; i < len(path); i++ {
+		switch path[i] {
+		case '*', '?', '[', '\\':
+			return true
+		}
+	}
+
+	return false
+}
+
 func (fsys *FS) getDir(path string) (*dir, error) {
 	parts := strings.Split(path, "/")
 
@@ -339,3 +1116,260 @@ func (fsys *FS) getDir(path string) (*dir, error) {
 
 	return cur, nil
 }
+
+// Clone returns a deep copy of fsys: every directory and file is
+// duplicated, and file contents are copied into fresh byte slices. The
+// clone shares no state with fsys, so writes to one are never visible
+// through the other. This makes it cheap to seed a test fixture once and
+// hand each test its own copy.
+func (fsys *FS) Clone() *FS {
+	fsys.mu.RLock()
+	defer fsys.mu.RUnlock()
+
+	clone := &FS{mu: new(sync.RWMutex)}
+	clone.root = cloneDir(fsys.root, clone)
+
+	return clone
+}
+
+func cloneDir(d *dir, fsys *FS) *dir {
+	cd := &dir{
+		dirEntry: cloneDirEntry(d.dirEntry),
+		fsys:     fsys,
+		entries:  make(map[string]fs.DirEntry, len(d.entries)),
+	}
+
+	for name, entry := range d.entries {
+		switch e := entry.(type) {
+		case *file:
+			data := make([]byte, len(e.data))
+			copy(data, e.data)
+
+			cd.entries[name] = &file{dirEntry: cloneDirEntry(e.dirEntry), data: data}
+		case *dir:
+			cd.entries[name] = cloneDir(e, fsys)
+		}
+	}
+
+	return cd
+}
+
+func cloneDirEntry(de *dirEntry) *dirEntry {
+	fi := *de.fileInfo
+	return &dirEntry{fileInfo: &fi}
+}
+
+// WriteTar writes the contents of fsys to w as a tar archive, preserving
+// each entry's path, mode, and modification time.
+func (fsys *FS) WriteTar(w io.Writer) error {
+	tw := tar.NewWriter(w)
+
+	err := fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if name == "." {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		hdr, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+
+		hdr.Name = name
+		if d.IsDir() {
+			hdr.Name += "/"
+		}
+
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			return nil
+		}
+
+		data, err := fsys.ReadFile(name)
+		if err != nil {
+			return err
+		}
+
+		_, err = tw.Write(data)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	return tw.Close()
+}
+
+// LoadTar populates fsys from a tar archive read from r, creating any
+// intermediate directories the entries need and preserving each entry's
+// mode and modification time. Entries outside fs.ValidPath form (e.g. an
+// absolute path or one containing "..") are rejected.
+func (fsys *FS) LoadTar(r io.Reader) error {
+	tr := tar.NewReader(r)
+
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		name := path.Clean(hdr.Name)
+		if name == "." {
+			continue
+		}
+		if !fs.ValidPath(name) {
+			return &fs.PathError{Op: "loadtar", Path: hdr.Name, Err: fs.ErrInvalid}
+		}
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := fsys.MkdirAll(name, fs.FileMode(hdr.Mode)); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if dirPath := path.Dir(name); dirPath != "." {
+				if err := fsys.MkdirAll(dirPath, 0755); err != nil {
+					return err
+				}
+			}
+
+			data, err := io.ReadAll(tr)
+			if err != nil {
+				return err
+			}
+
+			if err := fsys.WriteFile(name, data, fs.FileMode(hdr.Mode)); err != nil {
+				return err
+			}
+		default:
+			continue
+		}
+
+		if err := fsys.Chtimes(name, hdr.ModTime, hdr.ModTime); err != nil {
+			return err
+		}
+	}
+}
+
+// WriteZip writes the contents of fsys to w as a zip archive, preserving
+// each entry's path, mode, and modification time.
+func (fsys *FS) WriteZip(w io.Writer) error {
+	zw := zip.NewWriter(w)
+
+	err := fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if name == "." {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		hdr, err := zip.FileInfoHeader(info)
+		if err != nil {
+			return err
+		}
+
+		hdr.Name = name
+		if d.IsDir() {
+			hdr.Name += "/"
+		} else {
+			hdr.Method = zip.Deflate
+		}
+
+		zf, err := zw.CreateHeader(hdr)
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			return nil
+		}
+
+		data, err := fsys.ReadFile(name)
+		if err != nil {
+			return err
+		}
+
+		_, err = zf.Write(data)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	return zw.Close()
+}
+
+// LoadZip populates fsys from a zip archive read from r, creating any
+// intermediate directories the entries need and preserving each entry's
+// mode and modification time. r must support random access, per
+// archive/zip.NewReader; size is the total length of the archive.
+func (fsys *FS) LoadZip(r io.ReaderAt, size int64) error {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return err
+	}
+
+	for _, zf := range zr.File {
+		name := path.Clean("/" + zf.Name)[1:]
+		if name == "" {
+			continue
+		}
+		if !fs.ValidPath(name) {
+			return &fs.PathError{Op: "loadzip", Path: zf.Name, Err: fs.ErrInvalid}
+		}
+
+		if zf.FileInfo().IsDir() {
+			if err := fsys.MkdirAll(name, zf.Mode()); err != nil {
+				return err
+			}
+		} else {
+			if dirPath := path.Dir(name); dirPath != "." {
+				if err := fsys.MkdirAll(dirPath, 0755); err != nil {
+					return err
+				}
+			}
+
+			rc, err := zf.Open()
+			if err != nil {
+				return err
+			}
+
+			data, err := io.ReadAll(rc)
+			rc.Close()
+			if err != nil {
+				return err
+			}
+
+			if err := fsys.WriteFile(name, data, zf.Mode()); err != nil {
+				return err
+			}
+		}
+
+		if err := fsys.Chtimes(name, zf.Modified, zf.Modified); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
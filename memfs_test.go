@@ -1,19 +1,25 @@
 package memfs_test
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
+	"io"
 	"io/fs"
+	"os"
+	"sync"
 	"testing"
+	"time"
 
 	"memfs"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 )
 
 func TestMemFS(t *testing.T) {
 	rootFS := memfs.New()
-	err := rootFS.MkdirAll("foo/bar")
+	err := rootFS.MkdirAll("foo/bar", 0755)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -43,7 +49,7 @@ func TestMemFS(t *testing.T) {
 		t.Fatalf("WalkDir mismatch %s", diff)
 	}
 
-	err = rootFS.WriteFile("foo/baz/buz.txt", []byte("buz"))
+	err = rootFS.WriteFile("foo/baz/buz.txt", []byte("buz"), 0644)
 	if err == nil || !errors.Is(err, fs.ErrNotExist) {
 		t.Fatalf("Expected missing directory error but got none")
 	}
@@ -54,7 +60,7 @@ func TestMemFS(t *testing.T) {
 	}
 
 	data := []byte("baz")
-	err = rootFS.WriteFile("foo/bar/baz.txt", data)
+	err = rootFS.WriteFile("foo/bar/baz.txt", data, 0644)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -68,3 +74,495 @@ func TestMemFS(t *testing.T) {
 		t.Fatalf("write/read baz.txt mismatch %s", diff)
 	}
 }
+
+func TestOpenFile(t *testing.T) {
+	rootFS := memfs.New()
+
+	_, err := rootFS.OpenFile("foo.txt", os.O_RDONLY, 0)
+	if err == nil || !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("OpenFile on missing file: got %v, want ErrNotExist", err)
+	}
+
+	f, err := rootFS.OpenFile("foo.txt", os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := f.Write([]byte("hello world")); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := f.Seek(6, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := f.Write([]byte("there")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := fs.ReadFile(rootFS, "foo.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := cmp.Diff("hello there", string(content)); diff != "" {
+		t.Fatalf("seek/write mismatch %s", diff)
+	}
+
+	appender, err := rootFS.OpenFile("foo.txt", os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := appender.Write([]byte("!")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := appender.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	content, err = fs.ReadFile(rootFS, "foo.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := cmp.Diff("hello there!", string(content)); diff != "" {
+		t.Fatalf("append mismatch %s", diff)
+	}
+
+	if _, err := rootFS.OpenFile("foo.txt", os.O_RDWR|os.O_CREATE|os.O_EXCL, 0644); !errors.Is(err, fs.ErrExist) {
+		t.Fatalf("OpenFile with O_EXCL on existing file: got %v, want ErrExist", err)
+	}
+}
+
+func TestRenameRemove(t *testing.T) {
+	rootFS := memfs.New()
+	if err := rootFS.MkdirAll("foo/bar", 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := rootFS.WriteFile("foo/bar/a.txt", []byte("a"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := rootFS.Rename("foo/bar/a.txt", "foo/b.txt"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := fs.Stat(rootFS, "foo/bar/a.txt"); err == nil || !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("expected foo/bar/a.txt to be gone, got %v", err)
+	}
+
+	content, err := fs.ReadFile(rootFS, "foo/b.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := cmp.Diff("a", string(content)); diff != "" {
+		t.Fatalf("rename mismatch %s", diff)
+	}
+
+	if err := rootFS.Rename("foo/bar", "foo/baz"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := fs.Stat(rootFS, "foo/baz"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := rootFS.Remove("foo/b.txt"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := fs.Stat(rootFS, "foo/b.txt"); err == nil || !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("expected foo/b.txt to be removed, got %v", err)
+	}
+
+	if err := rootFS.WriteFile("foo/baz/c.txt", []byte("c"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := rootFS.Remove("foo/baz"); err == nil {
+		t.Fatal("expected error removing non-empty directory")
+	}
+
+	if err := rootFS.RemoveAll("foo/baz"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := fs.Stat(rootFS, "foo/baz"); err == nil || !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("expected foo/baz to be removed, got %v", err)
+	}
+
+	if err := rootFS.RemoveAll("no/such/path"); err != nil {
+		t.Fatalf("RemoveAll on missing path should be a no-op, got %v", err)
+	}
+}
+
+func TestRenameSameNameIsNoop(t *testing.T) {
+	rootFS := memfs.New()
+	if err := rootFS.WriteFile("a.txt", []byte("a"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := rootFS.Rename("a.txt", "a.txt"); err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := fs.ReadFile(rootFS, "a.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := cmp.Diff("a", string(content)); diff != "" {
+		t.Fatalf("self-rename mismatch %s", diff)
+	}
+}
+
+func TestConcurrentAccess(t *testing.T) {
+	rootFS := memfs.New()
+	if err := rootFS.MkdirAll("dir", 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		i := i
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+
+			name := fmt.Sprintf("dir/f%d.txt", i)
+			if err := rootFS.WriteFile(name, []byte("data"), 0644); err != nil {
+				t.Error(err)
+			}
+		}()
+
+		go func() {
+			defer wg.Done()
+
+			if _, err := fs.ReadDir(rootFS, "dir"); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+
+	wg.Wait()
+}
+
+func TestConcurrentStat(t *testing.T) {
+	rootFS := memfs.New()
+	if err := rootFS.WriteFile("a.txt", []byte("hello"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			if _, err := rootFS.Stat("a.txt"); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+
+	wg.Wait()
+}
+
+func TestConcurrentDirStat(t *testing.T) {
+	rootFS := memfs.New()
+	if err := rootFS.MkdirAll("dir", 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+
+	go func() {
+		defer wg.Done()
+
+		for i := 0; i < 50; i++ {
+			if _, err := rootFS.Stat("dir"); err != nil {
+				t.Error(err)
+			}
+		}
+	}()
+
+	go func() {
+		defer wg.Done()
+
+		for i := 0; i < 50; i++ {
+			if err := rootFS.Chtimes("dir", time.Unix(int64(i), 0), time.Unix(int64(i), 0)); err != nil {
+				t.Error(err)
+			}
+		}
+	}()
+
+	wg.Wait()
+}
+
+func TestChmodChtimesChown(t *testing.T) {
+	rootFS := memfs.New()
+	if err := rootFS.WriteFile("a.txt", []byte("a"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := rootFS.Chmod("a.txt", 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := fs.Stat(rootFS, "a.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if info.Mode() != 0600 {
+		t.Fatalf("Chmod: got mode %v, want %v", info.Mode(), fs.FileMode(0600))
+	}
+
+	atime := time.Unix(1000, 0)
+	mtime := time.Unix(2000, 0)
+	if err := rootFS.Chtimes("a.txt", atime, mtime); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err = fs.Stat(rootFS, "a.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !info.ModTime().Equal(mtime) {
+		t.Fatalf("Chtimes: got modTime %v, want %v", info.ModTime(), mtime)
+	}
+
+	sys, ok := info.Sys().(*memfs.Sys)
+	if !ok {
+		t.Fatalf("Sys(): got %T, want *memfs.Sys", info.Sys())
+	}
+
+	if !sys.Atime.Equal(atime) {
+		t.Fatalf("Chtimes: got atime %v, want %v", sys.Atime, atime)
+	}
+
+	if err := rootFS.Chown("a.txt", 42, 43); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err = fs.Stat(rootFS, "a.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sys = info.Sys().(*memfs.Sys)
+	if sys.Uid != 42 || sys.Gid != 43 {
+		t.Fatalf("Chown: got uid/gid %d/%d, want 42/43", sys.Uid, sys.Gid)
+	}
+}
+
+func TestFSExtensions(t *testing.T) {
+	rootFS := memfs.New()
+	if err := rootFS.MkdirAll("dir1/dir2", 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := rootFS.WriteFile("dir1/dir2/f1.txt", []byte("hello"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := rootFS.WriteFile("dir1/dir2/f2.txt", []byte("world"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := rootFS.ReadFile("dir1/dir2/f1.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := cmp.Diff("hello", string(content)); diff != "" {
+		t.Fatalf("ReadFile mismatch %s", diff)
+	}
+
+	entries, err := rootFS.ReadDir("dir1/dir2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(entries) != 2 {
+		t.Fatalf("ReadDir: got %d entries, want 2", len(entries))
+	}
+
+	if entries[0].Name() != "f1.txt" || entries[1].Name() != "f2.txt" {
+		t.Fatalf("ReadDir: got %q, %q, want sorted by filename", entries[0].Name(), entries[1].Name())
+	}
+
+	info, err := rootFS.Stat("dir1/dir2/f1.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if info.Size() != 5 {
+		t.Fatalf("Stat: got size %d, want 5", info.Size())
+	}
+
+	matches, err := rootFS.Glob("dir1/dir2/*.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantMatches := []string{"dir1/dir2/f1.txt", "dir1/dir2/f2.txt"}
+	if diff := cmp.Diff(wantMatches, matches, cmpopts.SortSlices(func(a, b string) bool { return a < b })); diff != "" {
+		t.Fatalf("Glob mismatch %s", diff)
+	}
+
+	sub, err := rootFS.Sub("dir1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	subContent, err := fs.ReadFile(sub, "dir2/f1.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := cmp.Diff("hello", string(subContent)); diff != "" {
+		t.Fatalf("Sub ReadFile mismatch %s", diff)
+	}
+
+	if err := rootFS.WriteFile("dir1/dir2/f3.txt", []byte("via root"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	subContent, err = fs.ReadFile(sub, "dir2/f3.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := cmp.Diff("via root", string(subContent)); diff != "" {
+		t.Fatalf("Sub should see writes made through root FS %s", diff)
+	}
+}
+
+func TestClone(t *testing.T) {
+	rootFS := memfs.New()
+	if err := rootFS.MkdirAll("dir", 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := rootFS.WriteFile("dir/a.txt", []byte("a"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	clone := rootFS.Clone()
+
+	if err := rootFS.WriteFile("dir/a.txt", []byte("changed"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := rootFS.WriteFile("dir/b.txt", []byte("b"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := clone.ReadFile("dir/a.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := cmp.Diff("a", string(content)); diff != "" {
+		t.Fatalf("Clone should not see later writes to the original %s", diff)
+	}
+
+	if _, err := clone.Stat("dir/b.txt"); err == nil || !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("Clone should not see files added to the original after cloning, got %v", err)
+	}
+}
+
+func TestTarRoundTrip(t *testing.T) {
+	rootFS := memfs.New()
+	if err := rootFS.MkdirAll("dir", 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := rootFS.WriteFile("dir/a.txt", []byte("a"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := rootFS.MkdirAll("dir/empty", 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	mtime := time.Unix(1000, 0)
+	if err := rootFS.Chtimes("dir/a.txt", mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := rootFS.WriteTar(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded := memfs.New()
+	if err := loaded.LoadTar(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := loaded.ReadFile("dir/a.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := cmp.Diff("a", string(content)); diff != "" {
+		t.Fatalf("tar round trip mismatch %s", diff)
+	}
+
+	info, err := loaded.Stat("dir/a.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !info.ModTime().Equal(mtime) {
+		t.Fatalf("tar round trip: got modTime %v, want %v", info.ModTime(), mtime)
+	}
+
+	if _, err := loaded.Stat("dir/empty"); err != nil {
+		t.Fatalf("tar round trip should preserve empty directories, got %v", err)
+	}
+}
+
+func TestZipRoundTrip(t *testing.T) {
+	rootFS := memfs.New()
+	if err := rootFS.MkdirAll("dir", 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := rootFS.WriteFile("dir/a.txt", []byte("a"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := rootFS.WriteZip(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded := memfs.New()
+	if err := loaded.LoadZip(bytes.NewReader(buf.Bytes()), int64(buf.Len())); err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := loaded.ReadFile("dir/a.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := cmp.Diff("a", string(content)); diff != "" {
+		t.Fatalf("zip round trip mismatch %s", diff)
+	}
+}
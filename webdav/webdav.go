@@ -0,0 +1,181 @@
+// Package webdav adapts a *memfs.FS into a golang.org/x/net/webdav.FileSystem,
+// so a memfs tree can back a webdav.Handler without a second in-memory
+// implementation. It lives in its own module so that pulling in
+// golang.org/x/net stays opt-in for callers that only need the core memfs
+// package.
+package webdav
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path"
+
+	"memfs"
+
+	"golang.org/x/net/webdav"
+)
+
+// WebDAV returns a webdav.FileSystem backed by fsys. Paths arrive from
+// golang.org/x/net/webdav as absolute, slash-separated names (e.g. "/",
+// "/foo/bar.txt"); WebDAV cleans each name (resolving "." and ".."
+// elements against the root) and strips the leading slash, so fsys only
+// ever sees names in fs.ValidPath form.
+func WebDAV(fsys *memfs.FS) webdav.FileSystem {
+	return &fileSystem{fsys: fsys}
+}
+
+type fileSystem struct {
+	fsys *memfs.FS
+}
+
+var _ webdav.FileSystem = (*fileSystem)(nil)
+
+// toValidPath translates a webdav-style absolute path into the relative,
+// fs.ValidPath form memfs expects.
+func toValidPath(op, name string) (string, error) {
+	name = path.Clean("/" + name)[1:]
+	if name == "" {
+		name = "."
+	}
+
+	if !fs.ValidPath(name) {
+		return "", &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+
+	return name, nil
+}
+
+func (fsys *fileSystem) Mkdir(ctx context.Context, name string, perm os.FileMode) error {
+	p, err := toValidPath("mkdir", name)
+	if err != nil {
+		return err
+	}
+
+	if _, err := fsys.fsys.Stat(p); err == nil {
+		return &fs.PathError{Op: "mkdir", Path: p, Err: fs.ErrExist}
+	}
+
+	if parent := path.Dir(p); parent != "." {
+		if fi, err := fsys.fsys.Stat(parent); err != nil || !fi.IsDir() {
+			return &fs.PathError{Op: "mkdir", Path: p, Err: fs.ErrNotExist}
+		}
+	}
+
+	return fsys.fsys.MkdirAll(p, perm)
+}
+
+func (fsys *fileSystem) OpenFile(ctx context.Context, name string, flag int, perm os.FileMode) (webdav.File, error) {
+	p, err := toValidPath("open", name)
+	if err != nil {
+		return nil, err
+	}
+
+	if fi, statErr := fsys.fsys.Stat(p); statErr == nil && fi.IsDir() {
+		if flag&(os.O_WRONLY|os.O_RDWR) != 0 {
+			return nil, &fs.PathError{Op: "open", Path: p, Err: fs.ErrPermission}
+		}
+
+		f, err := fsys.fsys.Open(p)
+		if err != nil {
+			return nil, err
+		}
+
+		d, ok := f.(fs.ReadDirFile)
+		if !ok {
+			return nil, &fs.PathError{Op: "open", Path: p, Err: fs.ErrInvalid}
+		}
+
+		return &dirFile{ReadDirFile: d}, nil
+	}
+
+	f, err := fsys.fsys.OpenFile(p, flag, perm)
+	if err != nil {
+		return nil, err
+	}
+
+	return &file{File: f}, nil
+}
+
+func (fsys *fileSystem) RemoveAll(ctx context.Context, name string) error {
+	p, err := toValidPath("removeall", name)
+	if err != nil {
+		return err
+	}
+
+	return fsys.fsys.RemoveAll(p)
+}
+
+func (fsys *fileSystem) Rename(ctx context.Context, oldName, newName string) error {
+	oldPath, err := toValidPath("rename", oldName)
+	if err != nil {
+		return err
+	}
+
+	newPath, err := toValidPath("rename", newName)
+	if err != nil {
+		return err
+	}
+
+	return fsys.fsys.Rename(oldPath, newPath)
+}
+
+func (fsys *fileSystem) Stat(ctx context.Context, name string) (os.FileInfo, error) {
+	p, err := toValidPath("stat", name)
+	if err != nil {
+		return nil, err
+	}
+
+	return fsys.fsys.Stat(p)
+}
+
+// file adapts a memfs.File, which already implements webdav.File's
+// Read/Seek/Close/Stat/Write, adding the Readdir method http.File requires.
+type file struct {
+	memfs.File
+}
+
+var _ webdav.File = (*file)(nil)
+
+func (f *file) Readdir(count int) ([]fs.FileInfo, error) {
+	return nil, &fs.PathError{Op: "readdir", Path: "", Err: errors.New("not a directory")}
+}
+
+// dirFile adapts an fs.ReadDirFile, which can Read, Stat, Close, and
+// ReadDir, into a webdav.File by rejecting writes and translating ReadDir's
+// fs.DirEntry results into the fs.FileInfo values Readdir returns.
+type dirFile struct {
+	fs.ReadDirFile
+}
+
+var _ webdav.File = (*dirFile)(nil)
+
+func (d *dirFile) Write(p []byte) (int, error) {
+	return 0, &fs.PathError{Op: "write", Path: "", Err: errors.New("is a directory")}
+}
+
+func (d *dirFile) Seek(offset int64, whence int) (int64, error) {
+	if offset != 0 || whence != 0 {
+		return 0, &fs.PathError{Op: "seek", Path: "", Err: fs.ErrInvalid}
+	}
+
+	return 0, nil
+}
+
+func (d *dirFile) Readdir(count int) ([]fs.FileInfo, error) {
+	entries, err := d.ReadDirFile.ReadDir(count)
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make([]fs.FileInfo, len(entries))
+	for i, entry := range entries {
+		infos[i], err = entry.Info()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return infos, nil
+}
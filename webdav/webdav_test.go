@@ -0,0 +1,215 @@
+package webdav_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"memfs"
+	memfswebdav "memfs/webdav"
+
+	"golang.org/x/net/webdav"
+)
+
+func TestFileSystem(t *testing.T) {
+	fsys := memfs.New()
+	if err := fsys.MkdirAll("dir", 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	wfs := memfswebdav.WebDAV(fsys)
+	ctx := context.Background()
+
+	f, err := wfs.OpenFile(ctx, "/dir/a.txt", os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := f.Write([]byte("hello")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := fsys.ReadFile("dir/a.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(content) != "hello" {
+		t.Fatalf("got %q, want %q", content, "hello")
+	}
+
+	if err := wfs.Rename(ctx, "/dir/a.txt", "/dir/b.txt"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := fsys.Stat("dir/b.txt"); err != nil {
+		t.Fatal(err)
+	}
+
+	dh, err := wfs.OpenFile(ctx, "/dir", os.O_RDONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dh.Close()
+
+	infos, err := dh.Readdir(-1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(infos) != 1 || infos[0].Name() != "b.txt" {
+		t.Fatalf("Readdir: got %v, want [b.txt]", infos)
+	}
+
+	if err := wfs.RemoveAll(ctx, "/dir"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := fsys.Stat("dir"); err == nil {
+		t.Fatal("expected dir to be removed after RemoveAll")
+	}
+}
+
+// TestHandler drives memfs through a real webdav.Handler, exercising
+// OpenFile, Rename, and RemoveAll the same way a WebDAV client would.
+func TestHandler(t *testing.T) {
+	fsys := memfs.New()
+	handler := &webdav.Handler{FileSystem: memfswebdav.WebDAV(fsys), LockSystem: webdav.NewMemLS()}
+	srv := httptest.NewServer(handler)
+	defer srv.Close()
+
+	put := func(target string, body string) {
+		req, err := http.NewRequest(http.MethodPut, srv.URL+target, strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode/100 != 2 {
+			t.Fatalf("PUT %s: got status %d", target, resp.StatusCode)
+		}
+	}
+
+	put("/a.txt", "hello world")
+
+	resp, err := http.Get(srv.URL + "/a.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	got, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(got) != "hello world" {
+		t.Fatalf("GET /a.txt: got %q, want %q", got, "hello world")
+	}
+
+	req, err := http.NewRequest("MOVE", srv.URL+"/a.txt", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Destination", srv.URL+"/b.txt")
+
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		t.Fatalf("MOVE: got status %d", resp.StatusCode)
+	}
+
+	if _, err := fsys.Stat("b.txt"); err != nil {
+		t.Fatalf("expected b.txt to exist after MOVE: %v", err)
+	}
+
+	req, err = http.NewRequest(http.MethodDelete, srv.URL+"/b.txt", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		t.Fatalf("DELETE: got status %d", resp.StatusCode)
+	}
+
+	if _, err := fsys.Stat("b.txt"); err == nil {
+		t.Fatal("expected b.txt to be removed after DELETE")
+	}
+}
+
+// TestFileSystemIndependentReaddirCursors guards against two concurrent
+// PROPFINDs on the same directory corrupting each other's paginated
+// Readdir(count) cursor.
+func TestFileSystemIndependentReaddirCursors(t *testing.T) {
+	fsys := memfs.New()
+	if err := fsys.MkdirAll("dir", 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"a.txt", "b.txt", "c.txt"} {
+		if err := fsys.WriteFile("dir/"+name, []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	wfs := memfswebdav.WebDAV(fsys)
+	ctx := context.Background()
+
+	dh1, err := wfs.OpenFile(ctx, "/dir", os.O_RDONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dh1.Close()
+
+	dh2, err := wfs.OpenFile(ctx, "/dir", os.O_RDONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dh2.Close()
+
+	first1, err := dh1.Readdir(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first2, err := dh2.Readdir(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if first1[0].Name() != "a.txt" || first2[0].Name() != "a.txt" {
+		t.Fatalf("Readdir(1): got %q, %q, want both to start at a.txt", first1[0].Name(), first2[0].Name())
+	}
+
+	rest1, err := dh1.Readdir(-1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(rest1) != 2 || rest1[0].Name() != "b.txt" || rest1[1].Name() != "c.txt" {
+		t.Fatalf("dh1 Readdir(-1): got %v, want [b.txt c.txt]", rest1)
+	}
+}